@@ -0,0 +1,39 @@
+// Expected Go output for simple_class.cpp's Point and Rectangle with
+// --layout=c-abi. A separate package from examples/expected_output.go
+// (the --layout=native output) since both declare Point/Rectangle and
+// can't coexist in one.
+//
+// Both types are POD under the Itanium ABI with no gaps to pad, so the
+// only change from the native-layout output is the compile-time size
+// assertion computed by internal/layout. Resource and SharedData aren't
+// POD and are unaffected by --layout=c-abi, so they're omitted here.
+package c_abi
+
+import "unsafe"
+
+type Point struct {
+    X int32
+    Y int32
+}
+
+var _ [unsafe.Sizeof(Point{}) - 8]byte
+var _ [8 - unsafe.Sizeof(Point{})]byte
+
+func NewPoint(x, y int32) Point {
+    return Point{X: x, Y: y}
+}
+
+type Rectangle struct {
+    TopLeft     Point
+    BottomRight Point
+}
+
+var _ [unsafe.Sizeof(Rectangle{}) - 16]byte
+var _ [16 - unsafe.Sizeof(Rectangle{})]byte
+
+func NewRectangle(tl, br Point) Rectangle {
+    return Rectangle{
+        TopLeft:     tl,
+        BottomRight: br,
+    }
+}
@@ -2,7 +2,10 @@
 
 package main
 
-import "fmt"
+import (
+    "fmt"
+    "runtime"
+)
 
 type Point struct {
     X int32
@@ -63,15 +66,29 @@ func (r *Rectangle) Area() int32 {
 }
 
 type Resource struct {
-    Data []int32
-    Size uint
+    Data     []int32
+    Size     uint
+    released bool
 }
 
 func NewResource(n uint) *Resource {
-    return &Resource{
+    r := &Resource{
         Data: make([]int32, n),
         Size: n,
     }
+    runtime.SetFinalizer(r, (*Resource).Release)
+    return r
+}
+
+// Release runs the C++ destructor body. It is safe to call more than
+// once: the finalizer also calls it, so an explicit Release followed by
+// garbage collection must not double-free.
+func (r *Resource) Release() {
+    if r.released {
+        return
+    }
+    r.released = true
+    r.Data = nil
 }
 
 func (r *Resource) Get(index uint) int32 {
@@ -0,0 +1,37 @@
+// Expected Go output demonstrating --raii=defer for a stack-constructed
+// RAII class: a constructor that returns T by value, the way NewPoint
+// does, rather than *T. A separate package purely so each --raii policy
+// has its own self-contained example; this class doesn't appear in
+// examples/expected_output.go.
+
+package raii_defer
+
+type FileHandle struct {
+    fd       int32
+    released bool
+}
+
+func NewFileHandle(fd int32) FileHandle {
+    return FileHandle{fd: fd}
+}
+
+func (f *FileHandle) FD() int32 {
+    return f.fd
+}
+
+// Release runs the C++ destructor body (closing fd). Idempotent: safe to
+// call more than once.
+func (f *FileHandle) Release() {
+    if f.released {
+        return
+    }
+    f.released = true
+    f.fd = -1
+}
+
+func readHeader(fd int32) int32 {
+    f := NewFileHandle(fd)
+    defer f.Release()
+
+    return f.FD()
+}
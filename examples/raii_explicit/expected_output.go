@@ -0,0 +1,42 @@
+// Expected Go output for simple_class.cpp's Resource under --raii=explicit.
+// Compare with Resource in examples/expected_output.go (the --raii=both
+// default): Release is emitted either way, but explicit mode wires no
+// automatic call to it, so it's the caller's job to call Release.
+
+package raii_explicit
+
+type Resource struct {
+    Data     []int32
+    Size     uint
+    released bool
+}
+
+// --raii=explicit: no runtime.SetFinalizer wiring, unlike the default.
+func NewResource(n uint) *Resource {
+    return &Resource{
+        Data: make([]int32, n),
+        Size: n,
+    }
+}
+
+// Release runs the C++ destructor body. Idempotent: safe to call more
+// than once.
+func (r *Resource) Release() {
+    if r.released {
+        return
+    }
+    r.released = true
+    r.Data = nil
+}
+
+func (r *Resource) Get(index uint) int32 {
+    return r.Data[index]
+}
+
+func (r *Resource) Set(index uint, value int32) {
+    r.Data[index] = value
+}
+
+func (r *Resource) GetSize() uint {
+    return r.Size
+}
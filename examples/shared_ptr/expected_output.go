@@ -0,0 +1,70 @@
+// Expected Go output for simple_class.cpp's SharedData under the default
+// (rc) shared_ptr lowering, i.e. without --shared-ptr=gc. A separate
+// package from examples/expected_output.go since both declare
+// SharedData/NewSharedData and can't coexist in one.
+//
+// Compare with SharedData in expected_output.go, which is the
+// --shared-ptr=gc output (plain *SharedData, no refcounting) — the mode
+// the example's author already favored, and the one internal/sharedptr
+// confirms is safe here since SharedData never holds a shared_ptr back to
+// anything (see internal/sharedptr.DetectCycles).
+
+package shared_ptr
+
+import "sync/atomic"
+
+type Shared[T any] struct {
+    ptr *T
+    rc  *atomic.Int64
+}
+
+func NewShared[T any](v T) Shared[T] {
+    rc := &atomic.Int64{}
+    rc.Store(1)
+    return Shared[T]{ptr: &v, rc: rc}
+}
+
+func (s Shared[T]) Clone() Shared[T] {
+    s.rc.Add(1)
+    return s
+}
+
+// Release takes a pointer receiver: it nils out s.ptr on the caller's own
+// handle once the refcount hits zero, not on a throwaway copy.
+func (s *Shared[T]) Release() {
+    if s.rc.Add(-1) == 0 {
+        s.ptr = nil
+    }
+}
+
+type Weak[T any] struct {
+    ptr *T
+    rc  *atomic.Int64
+}
+
+func (s Shared[T]) Weaken() Weak[T] {
+    return Weak[T]{ptr: s.ptr, rc: s.rc}
+}
+
+func (w Weak[T]) Lock() *T {
+    if w.rc.Load() == 0 {
+        return nil
+    }
+    return w.ptr
+}
+
+type SharedData struct {
+    Message string
+}
+
+func NewSharedData(msg string) Shared[SharedData] {
+    return NewShared(SharedData{Message: msg})
+}
+
+func (s *SharedData) GetMessage() string {
+    return s.Message
+}
+
+func (s *SharedData) SetMessage(msg string) {
+    s.Message = msg
+}
@@ -0,0 +1,11 @@
+package shared_ptr
+
+import "testing"
+
+func TestReleaseNilsPointerOnCallersHandle(t *testing.T) {
+	s := NewShared(42)
+	s.Release()
+	if s.ptr != nil {
+		t.Fatal("Release with refcount hitting zero left the caller's handle non-nil")
+	}
+}
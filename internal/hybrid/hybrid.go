@@ -0,0 +1,176 @@
+// Package hybrid decides, per class, whether the native Go emitter or the
+// cgo fallback handles a class, and generates the cgo wrapper for classes
+// that go that route.
+package hybrid
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cmc-labo/hybrid-transpiler/internal/ir"
+)
+
+// Policy is the value of the --hybrid flag.
+type Policy string
+
+const (
+	// Auto uses cgo only for classes the native emitter can't handle.
+	Auto Policy = "auto"
+	// Always routes every class through the cgo fallback.
+	Always Policy = "always"
+	// Never forces native transpilation even for unsupported classes,
+	// leaving Unsupported classes to fail downstream instead. Classes
+	// pinned in a Manifest still go through cgo.
+	Never Policy = "never"
+)
+
+// ParsePolicy parses the --hybrid flag value, defaulting to Auto.
+func ParsePolicy(s string) (Policy, error) {
+	switch Policy(s) {
+	case "", Auto:
+		return Auto, nil
+	case Always:
+		return Always, nil
+	case Never:
+		return Never, nil
+	default:
+		return "", fmt.Errorf("hybrid: unknown --hybrid value %q (want auto, always, or never)", s)
+	}
+}
+
+// ClassPin is one entry in a hybrid.yaml manifest.
+type ClassPin struct {
+	Name    string
+	CFlags  []string
+	LDFlags []string
+}
+
+// Manifest is the parsed form of a hybrid.yaml file: classes pinned to the
+// cgo path regardless of Policy, with the exact compiler/linker flags to
+// use for each.
+type Manifest struct {
+	Classes []ClassPin
+}
+
+// Pin returns the manifest entry for class name, if any.
+func (m Manifest) Pin(name string) (ClassPin, bool) {
+	for _, c := range m.Classes {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return ClassPin{}, false
+}
+
+// NeedsCgo decides whether class should be routed through the cgo
+// fallback rather than transpiled natively, given policy and an optional
+// manifest (pass a zero Manifest when there isn't one).
+func NeedsCgo(c ir.Class, policy Policy, m Manifest) bool {
+	if _, pinned := m.Pin(c.Name); pinned {
+		return true
+	}
+	switch policy {
+	case Always:
+		return true
+	case Never:
+		return false
+	default: // Auto
+		return len(c.Unsupported) > 0
+	}
+}
+
+// EmitGoWrapper generates the Go side of the cgo fallback for class c:
+// the cgo preamble, an opaque-handle struct, and forwarding methods. pin
+// supplies the CFLAGS/LDFLAGS for the preamble; it's the caller's
+// responsibility to have confirmed NeedsCgo(c, ...) first.
+//
+// Arguments and return values forward through C.<ctype>(...) conversions
+// per bindParam/bindReturn; a parameter or return type neither function
+// recognizes is forwarded best-effort through unsafe.Pointer with a
+// TODO(hybrid) comment rather than silently dropped (see
+// docs/design/hybrid-cgo-fallback.md's "Known limitations").
+func EmitGoWrapper(c ir.Class, pin ClassPin) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "package main\n\n")
+	fmt.Fprintf(&b, "// #cgo CFLAGS: %s\n", strings.Join(pin.CFlags, " "))
+	fmt.Fprintf(&b, "// #cgo LDFLAGS: %s\n", strings.Join(pin.LDFlags, " "))
+	fmt.Fprintf(&b, "// #include \"%s_shim.h\"\n", c.Name)
+	fmt.Fprintf(&b, "import \"C\"\n")
+	fmt.Fprintf(&b, "import (\n\t\"runtime\"\n\t\"unsafe\"\n)\n\n")
+	fmt.Fprintf(&b, "type %s struct {\n\thandle unsafe.Pointer\n}\n\n", c.Name)
+	fmt.Fprintf(&b, "func New%s() *%s {\n", c.Name, c.Name)
+	fmt.Fprintf(&b, "\tw := &%s{handle: unsafe.Pointer(C.%s_New())}\n", c.Name, c.Name)
+	fmt.Fprintf(&b, "\truntime.SetFinalizer(w, (*%s).release)\n", c.Name)
+	fmt.Fprintf(&b, "\treturn w\n}\n\n")
+	for _, m := range c.Methods {
+		bindings := make([]argBinding, len(m.Params))
+		goParams := make([]string, len(m.Params))
+		callArgs := make([]string, len(m.Params))
+		for i, p := range m.Params {
+			bindings[i] = bindParam(p, i)
+			goParams[i] = bindings[i].GoParam
+			callArgs[i] = bindings[i].CallArg
+		}
+		ret := bindReturn(m.Return)
+
+		sig := fmt.Sprintf("func (w *%s) %s(%s)", c.Name, m.Name, strings.Join(goParams, ", "))
+		if ret.GoType != "" {
+			sig += " " + ret.GoType
+		}
+		fmt.Fprintf(&b, "%s {\n", sig)
+		for _, bnd := range bindings {
+			for _, line := range bnd.Prelude {
+				fmt.Fprintf(&b, "\t%s\n", line)
+			}
+		}
+		for _, bnd := range bindings {
+			for _, line := range bnd.Cleanup {
+				fmt.Fprintf(&b, "\t%s\n", line)
+			}
+		}
+		allArgs := append([]string{"w.handle"}, callArgs...)
+		call := fmt.Sprintf("C.%s_%s(%s)", c.Name, m.Name, strings.Join(allArgs, ", "))
+		if ret.GoType == "" {
+			fmt.Fprintf(&b, "\t%s\n", call)
+		} else {
+			fmt.Fprintf(&b, "\treturn %s\n", ret.Wrap(call))
+		}
+		fmt.Fprintf(&b, "}\n\n")
+	}
+	fmt.Fprintf(&b, "func (w *%s) release() {\n", c.Name)
+	fmt.Fprintf(&b, "\tC.%s_Delete(w.handle)\n", c.Name)
+	fmt.Fprintf(&b, "}\n")
+	return b.String()
+}
+
+// EmitCShim generates the extern "C" shim that adapts class c's public
+// methods to a C ABI the Go wrapper can call through cgo. Parameter and
+// return C types come from the same bindParam/bindReturn logic
+// EmitGoWrapper uses, so the two sides' signatures match.
+func EmitCShim(c ir.Class) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#include \"%s.h\"\n\n", c.Name)
+	fmt.Fprintf(&b, "extern \"C\" {\n\n")
+	fmt.Fprintf(&b, "void* %s_New() { return new %s(); }\n", c.Name, c.Name)
+	fmt.Fprintf(&b, "void %s_Delete(void* handle) { delete static_cast<%s*>(handle); }\n", c.Name, c.Name)
+	for _, m := range c.Methods {
+		cParams := make([]string, len(m.Params))
+		fwdArgs := make([]string, len(m.Params))
+		for i, p := range m.Params {
+			bnd := bindParam(p, i)
+			cParams[i] = bnd.CParam
+			fwdArgs[i] = paramName(p, i)
+		}
+		ret := bindReturn(m.Return)
+
+		params := append([]string{"void* handle"}, cParams...)
+		call := fmt.Sprintf("static_cast<%s*>(handle)->%s(%s)", c.Name, m.Name, strings.Join(fwdArgs, ", "))
+		if ret.CType == "void" {
+			fmt.Fprintf(&b, "void %s_%s(%s) { %s; }\n", c.Name, m.Name, strings.Join(params, ", "), call)
+		} else {
+			fmt.Fprintf(&b, "%s %s_%s(%s) { return %s; }\n", ret.CType, c.Name, m.Name, strings.Join(params, ", "), call)
+		}
+	}
+	fmt.Fprintf(&b, "\n}\n")
+	return b.String()
+}
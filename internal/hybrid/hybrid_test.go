@@ -0,0 +1,152 @@
+package hybrid
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cmc-labo/hybrid-transpiler/internal/ir"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Policy
+		wantErr bool
+	}{
+		{"", Auto, false},
+		{"auto", Auto, false},
+		{"always", Always, false},
+		{"never", Never, false},
+		{"sometimes", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePolicy(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePolicy(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParsePolicy(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNeedsCgo(t *testing.T) {
+	native := ir.Class{Name: "Point"}
+	unsupported := ir.Class{Name: "LegacyMatcher", Unsupported: []string{"uses std::regex"}}
+
+	tests := []struct {
+		name   string
+		class  ir.Class
+		policy Policy
+		m      Manifest
+		want   bool
+	}{
+		{"auto native", native, Auto, Manifest{}, false},
+		{"auto unsupported", unsupported, Auto, Manifest{}, true},
+		{"always native", native, Always, Manifest{}, true},
+		{"never unsupported", unsupported, Never, Manifest{}, false},
+		{"pinned overrides never", native, Never, Manifest{Classes: []ClassPin{{Name: "Point"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NeedsCgo(tt.class, tt.policy, tt.m); got != tt.want {
+				t.Errorf("NeedsCgo(%s, %s) = %v, want %v", tt.class.Name, tt.policy, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmitGoWrapper(t *testing.T) {
+	c := ir.Class{
+		Name:    "LegacyMatcher",
+		Methods: []ir.Method{{Name: "Reset"}},
+	}
+	pin := ClassPin{
+		Name:    "LegacyMatcher",
+		CFlags:  []string{"-Ithird_party/legacy_matcher/include"},
+		LDFlags: []string{"-Lthird_party/legacy_matcher/lib", "-llegacy_matcher"},
+	}
+	out := EmitGoWrapper(c, pin)
+
+	for _, want := range []string{
+		"// #cgo CFLAGS: -Ithird_party/legacy_matcher/include",
+		"// #cgo LDFLAGS: -Lthird_party/legacy_matcher/lib -llegacy_matcher",
+		"type LegacyMatcher struct {",
+		"handle unsafe.Pointer",
+		"runtime.SetFinalizer(w, (*LegacyMatcher).release)",
+		"C.LegacyMatcher_Reset(w.handle)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("EmitGoWrapper output missing %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestEmitCShim(t *testing.T) {
+	c := ir.Class{
+		Name:    "LegacyMatcher",
+		Methods: []ir.Method{{Name: "Reset"}},
+	}
+	out := EmitCShim(c)
+
+	for _, want := range []string{
+		`void* LegacyMatcher_New() { return new LegacyMatcher(); }`,
+		`void LegacyMatcher_Delete(void* handle) { delete static_cast<LegacyMatcher*>(handle); }`,
+		`void LegacyMatcher_Reset(void* handle) { static_cast<LegacyMatcher*>(handle)->Reset(); }`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("EmitCShim output missing %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func matcherWithArgs() ir.Class {
+	return ir.Class{
+		Name: "LegacyMatcher",
+		Methods: []ir.Method{
+			{
+				Name:   "Match",
+				Params: []ir.Param{{Name: "input", Type: ir.Type{GoName: "string"}}, {Name: "flags", Type: ir.Type{GoName: "int32"}}},
+				Return: ir.Type{GoName: "bool"},
+			},
+		},
+	}
+}
+
+func TestEmitGoWrapperForwardsArgsAndReturn(t *testing.T) {
+	out := EmitGoWrapper(matcherWithArgs(), ClassPin{Name: "LegacyMatcher"})
+
+	for _, want := range []string{
+		"func (w *LegacyMatcher) Match(input string, flags int32) bool {",
+		"cInput := C.CString(input)",
+		"defer C.free(unsafe.Pointer(cInput))",
+		"C.LegacyMatcher_Match(w.handle, cInput, C.int32_t(flags))",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("EmitGoWrapper output missing %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestEmitCShimMatchesArgsAndReturn(t *testing.T) {
+	out := EmitCShim(matcherWithArgs())
+
+	want := `_Bool LegacyMatcher_Match(void* handle, const char* input, int32_t flags) { return static_cast<LegacyMatcher*>(handle)->Match(input, flags); }`
+	if !strings.Contains(out, want) {
+		t.Errorf("EmitCShim output missing %q, got:\n%s", want, out)
+	}
+}
+
+func TestEmitGoWrapperFlagsUnsupportedParamType(t *testing.T) {
+	c := ir.Class{
+		Name: "LegacyMatcher",
+		Methods: []ir.Method{
+			{Name: "SetCallback", Params: []ir.Param{{Name: "cb", Type: ir.Type{GoName: "func()"}}}},
+		},
+	}
+	out := EmitGoWrapper(c, ClassPin{Name: "LegacyMatcher"})
+	if !strings.Contains(out, `TODO(hybrid): unsupported param type "func()"`) {
+		t.Errorf("EmitGoWrapper should flag the unsupported param type, got:\n%s", out)
+	}
+}
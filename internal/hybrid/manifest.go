@@ -0,0 +1,92 @@
+package hybrid
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseManifest parses a hybrid.yaml manifest. Only the small subset of
+// YAML the manifest actually needs is supported (a top-level "classes"
+// list of name/cflags/ldflags entries) — pulling in a full YAML library
+// isn't worth it for one small, fixed-shape file.
+func ParseManifest(r io.Reader) (Manifest, error) {
+	var m Manifest
+	var cur *ClassPin
+	var field string // "cflags" or "ldflags", while inside one of those lists
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if trimmed := strings.TrimSpace(line); trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "classes:"):
+			field = ""
+			continue
+
+		case strings.HasPrefix(strings.TrimLeft(line, " "), "- name:"):
+			if cur != nil {
+				m.Classes = append(m.Classes, *cur)
+			}
+			name, err := unquote(afterColon(line))
+			if err != nil {
+				return Manifest{}, fmt.Errorf("hybrid: manifest line %d: %w", lineNo, err)
+			}
+			cur = &ClassPin{Name: name}
+			field = ""
+
+		case strings.HasSuffix(strings.TrimSpace(line), "cflags:"):
+			field = "cflags"
+
+		case strings.HasSuffix(strings.TrimSpace(line), "ldflags:"):
+			field = "ldflags"
+
+		case strings.HasPrefix(strings.TrimSpace(line), "- "):
+			if cur == nil || field == "" {
+				return Manifest{}, fmt.Errorf("hybrid: manifest line %d: list entry outside cflags/ldflags", lineNo)
+			}
+			val, err := unquote(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "-")))
+			if err != nil {
+				return Manifest{}, fmt.Errorf("hybrid: manifest line %d: %w", lineNo, err)
+			}
+			switch field {
+			case "cflags":
+				cur.CFlags = append(cur.CFlags, val)
+			case "ldflags":
+				cur.LDFlags = append(cur.LDFlags, val)
+			}
+
+		default:
+			return Manifest{}, fmt.Errorf("hybrid: manifest line %d: unrecognized %q", lineNo, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Manifest{}, err
+	}
+	if cur != nil {
+		m.Classes = append(m.Classes, *cur)
+	}
+	return m, nil
+}
+
+func afterColon(line string) string {
+	_, v, _ := strings.Cut(line, ":")
+	return strings.TrimSpace(v)
+}
+
+func unquote(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	if s[0] == '"' {
+		return strconv.Unquote(s)
+	}
+	return s, nil
+}
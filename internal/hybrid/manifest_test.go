@@ -0,0 +1,71 @@
+package hybrid
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseManifest(t *testing.T) {
+	in := `classes:
+  - name: LegacyMatcher
+    cflags:
+      - "-Ithird_party/legacy_matcher/include"
+    ldflags:
+      - "-Lthird_party/legacy_matcher/lib"
+      - "-llegacy_matcher"
+  - name: OtherClass
+    cflags:
+      - "-Ivendor/other"
+`
+	m, err := ParseManifest(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("ParseManifest: %v", err)
+	}
+	if len(m.Classes) != 2 {
+		t.Fatalf("got %d classes, want 2", len(m.Classes))
+	}
+
+	lm, ok := m.Pin("LegacyMatcher")
+	if !ok {
+		t.Fatal("LegacyMatcher not found in manifest")
+	}
+	if want := []string{"-Ithird_party/legacy_matcher/include"}; !equal(lm.CFlags, want) {
+		t.Errorf("CFlags = %v, want %v", lm.CFlags, want)
+	}
+	if want := []string{"-Lthird_party/legacy_matcher/lib", "-llegacy_matcher"}; !equal(lm.LDFlags, want) {
+		t.Errorf("LDFlags = %v, want %v", lm.LDFlags, want)
+	}
+
+	if _, ok := m.Pin("Point"); ok {
+		t.Error("Point unexpectedly pinned")
+	}
+}
+
+func TestParseManifestExampleFile(t *testing.T) {
+	f, err := os.Open("../../examples/hybrid.yaml")
+	if err != nil {
+		t.Fatalf("open example manifest: %v", err)
+	}
+	defer f.Close()
+
+	m, err := ParseManifest(f)
+	if err != nil {
+		t.Fatalf("ParseManifest(examples/hybrid.yaml): %v", err)
+	}
+	if _, ok := m.Pin("LegacyMatcher"); !ok {
+		t.Error("examples/hybrid.yaml: expected a LegacyMatcher pin")
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,116 @@
+package hybrid
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cmc-labo/hybrid-transpiler/internal/ir"
+)
+
+// numericCType maps a Go primitive type name to the C type cgo exposes it
+// as (C.int32_t, C.double, ...). stdint.h's fixed-width names are used
+// throughout rather than C's native int/long/etc., since the shim header
+// these wrappers #include already needs stdint.h for the handle-style
+// declarations and fixed widths avoid int/long varying by platform.
+var numericCType = map[string]string{
+	"int8": "int8_t", "int16": "int16_t", "int32": "int32_t", "int64": "int64_t",
+	"uint8": "uint8_t", "uint16": "uint16_t", "uint32": "uint32_t", "uint64": "uint64_t",
+	"uint": "uintptr_t", "int": "intptr_t",
+	"float32": "float", "float64": "double",
+	"bool": "_Bool",
+}
+
+// argBinding is everything EmitGoWrapper needs to forward one parameter
+// through cgo: the Go-side parameter declaration, any Go statements that
+// have to run before the call (e.g. a C string conversion) and after it
+// (freeing that conversion), the expression passed to the C call, and the
+// C-side parameter type EmitCShim needs to declare to match.
+type argBinding struct {
+	GoParam     string
+	Prelude     []string
+	Cleanup     []string
+	CallArg     string
+	CParam      string
+	Unsupported bool
+}
+
+func paramName(p ir.Param, i int) string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return fmt.Sprintf("arg%d", i)
+}
+
+// bindParam decides how to forward a single parameter. Numeric primitives
+// and bool convert directly via a C.<ctype>(...) cast; string goes
+// through C.CString with a matching deferred C.free. Anything else (a
+// pointer to another wrapped class, a template instantiation, ...) has no
+// general cgo-safe lowering here yet, so it's forwarded best-effort as an
+// unsafe.Pointer to the Go value and flagged Unsupported — see
+// docs/design/hybrid-cgo-fallback.md's "Known limitations" section.
+func bindParam(p ir.Param, i int) argBinding {
+	name := paramName(p, i)
+	if ctype, ok := numericCType[p.Type.GoName]; ok {
+		return argBinding{
+			GoParam: fmt.Sprintf("%s %s", name, p.Type.GoName),
+			CallArg: fmt.Sprintf("C.%s(%s)", ctype, name),
+			CParam:  ctype + " " + name,
+		}
+	}
+	if p.Type.GoName == "string" {
+		cVar := "c" + strings.ToUpper(name[:1]) + name[1:]
+		return argBinding{
+			GoParam: fmt.Sprintf("%s string", name),
+			Prelude: []string{fmt.Sprintf("%s := C.CString(%s)", cVar, name)},
+			Cleanup: []string{fmt.Sprintf("defer C.free(unsafe.Pointer(%s))", cVar)},
+			CallArg: cVar,
+			CParam:  "const char* " + name,
+		}
+	}
+	return argBinding{
+		GoParam:     fmt.Sprintf("%s %s", name, p.Type.GoName),
+		CallArg:     fmt.Sprintf("unsafe.Pointer(&%s) /* TODO(hybrid): unsupported param type %q */", name, p.Type.GoName),
+		CParam:      "void* " + name,
+		Unsupported: true,
+	}
+}
+
+// returnBinding mirrors argBinding for a method's return value.
+type returnBinding struct {
+	GoType      string
+	CType       string
+	Wrap        func(cExpr string) string
+	Unsupported bool
+}
+
+func bindReturn(t ir.Type) returnBinding {
+	if t.GoName == "" {
+		return returnBinding{CType: "void", Wrap: func(cExpr string) string { return cExpr }}
+	}
+	if ctype, ok := numericCType[t.GoName]; ok {
+		return returnBinding{
+			GoType: t.GoName,
+			CType:  ctype,
+			Wrap:   func(cExpr string) string { return fmt.Sprintf("%s(%s)", t.GoName, cExpr) },
+		}
+	}
+	if t.GoName == "string" {
+		// The shim is assumed to return a pointer the C++ object still
+		// owns (e.g. std::string::c_str()); nothing here frees it. A
+		// shim returning a freshly allocated buffer needs its own
+		// ownership convention, which isn't modeled yet.
+		return returnBinding{
+			GoType: "string",
+			CType:  "const char*",
+			Wrap:   func(cExpr string) string { return fmt.Sprintf("C.GoString(%s)", cExpr) },
+		}
+	}
+	return returnBinding{
+		GoType: t.GoName,
+		CType:  "void*",
+		Wrap: func(cExpr string) string {
+			return fmt.Sprintf("%s(unsafe.Pointer(%s)) /* TODO(hybrid): unsupported return type %q */", t.GoName, cExpr, t.GoName)
+		},
+		Unsupported: true,
+	}
+}
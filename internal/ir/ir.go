@@ -0,0 +1,70 @@
+// Package ir holds the class-level representation that the lowering
+// passes under internal/ operate on. It's the boundary between the (not
+// yet implemented) C++ front end and the Go emitter passes: front-end
+// work populates a Class, passes read and annotate it, the emitter turns
+// it into Go source.
+package ir
+
+// Type describes a single C++ type and how it maps to Go.
+type Type struct {
+	CppName string // e.g. "std::vector<int32_t>", "int32_t"
+	GoName  string // e.g. "[]int32", "int32"
+	Size    int    // size in bytes under the target C++ ABI, 0 if unknown
+	Align   int    // alignment in bytes under the target C++ ABI, 0 if unknown
+}
+
+// Field is a class data member.
+type Field struct {
+	Name string
+	Type Type
+}
+
+// Param is a method or constructor parameter.
+type Param struct {
+	Name string
+	Type Type
+}
+
+// Method is a class member function already lowered to a Go body; passes
+// in this tree work at the class/field/method-signature level, not on
+// function bodies.
+type Method struct {
+	Name   string
+	Params []Param
+	Return Type
+}
+
+// Class is a C++ class or struct as seen by the lowering passes.
+type Class struct {
+	Name    string
+	Fields  []Field
+	Methods []Method
+
+	// Destructor is non-nil when the class has a user-defined, non-trivial
+	// destructor. See internal/raii.
+	Destructor *Destructor
+
+	// HeapConstructed is true when the class is always returned by pointer
+	// from its constructors (a New* function returning *T), false when
+	// constructors return T by value.
+	HeapConstructed bool
+
+	// PackPragma is the #pragma pack(N) / __attribute__((packed)) value in
+	// effect for this class, or 0 if none. See internal/layout.
+	PackPragma int
+
+	// Unsupported lists native-transpilation blockers found on this class
+	// (template SFINAE, virtual inheritance, ...). See internal/hybrid.
+	Unsupported []string
+
+	// SharedFields lists the names of fields whose C++ type is a
+	// std::shared_ptr<OtherClass>, keyed by the other class's name. See
+	// internal/sharedptr.
+	SharedFields map[string]string
+}
+
+// Destructor marks a class as RAII: cleanup work the native Go destructor
+// pass has to preserve somehow, since Go has no implicit destructor call.
+type Destructor struct {
+	Body string // already-lowered Go statements forming the cleanup
+}
@@ -0,0 +1,124 @@
+// Package layout computes C++-ABI-compatible struct layouts (offsets,
+// padding, and overall size/alignment) so the emitter can produce Go
+// structs that are bit-for-bit compatible with the original C++ layout —
+// for shared-memory IPC, mmap'd files, or cgo handoff.
+package layout
+
+import (
+	"fmt"
+
+	"github.com/cmc-labo/hybrid-transpiler/internal/ir"
+)
+
+// Mode is the value of the --layout flag.
+type Mode string
+
+const (
+	// Native lets the Go compiler choose field order and padding.
+	Native Mode = "native"
+	// CABI preserves the original C++ ABI layout, inserting explicit pad
+	// fields and a compile-time size assertion.
+	CABI Mode = "c-abi"
+)
+
+// ParseMode parses the --layout flag value, defaulting to Native.
+func ParseMode(s string) (Mode, error) {
+	switch Mode(s) {
+	case "", Native:
+		return Native, nil
+	case CABI:
+		return CABI, nil
+	default:
+		return "", fmt.Errorf("layout: unknown --layout value %q (want native or c-abi)", s)
+	}
+}
+
+// PlacedField is one field of the computed layout: either a real class
+// field, or synthetic padding needed to reach the next field's alignment
+// (or the struct's trailing alignment).
+type PlacedField struct {
+	Name      string // the field's Go name, or "_padN" for padding
+	Offset    int
+	Size      int
+	IsPadding bool
+}
+
+// Layout is the result of computing a class's c-abi struct layout.
+type Layout struct {
+	Fields []PlacedField
+	Size   int
+	Align  int
+}
+
+// fieldAlign returns the effective alignment of a field, clamped by a
+// #pragma pack(N) / __attribute__((packed)) value (0 means no clamp).
+func fieldAlign(t ir.Type, pack int) int {
+	a := t.Align
+	if a <= 0 {
+		a = 1
+	}
+	if pack > 0 && pack < a {
+		return pack
+	}
+	return a
+}
+
+func roundUp(n, align int) int {
+	if align <= 1 {
+		return n
+	}
+	if rem := n % align; rem != 0 {
+		return n + (align - rem)
+	}
+	return n
+}
+
+// Compute lays out c's fields following the Itanium C++ ABI rule: each
+// field is placed at the next offset satisfying its own alignment
+// (clamped by c.PackPragma), and the struct's total size is rounded up to
+// the alignment of its most-aligned member.
+func Compute(c ir.Class) Layout {
+	var out Layout
+	offset := 0
+	structAlign := 1
+
+	padCount := 0
+	for _, f := range c.Fields {
+		align := fieldAlign(f.Type, c.PackPragma)
+		if align > structAlign {
+			structAlign = align
+		}
+		aligned := roundUp(offset, align)
+		if gap := aligned - offset; gap > 0 {
+			out.Fields = append(out.Fields, PlacedField{
+				Name: fmt.Sprintf("_pad%d", padCount), Offset: offset, Size: gap, IsPadding: true,
+			})
+			padCount++
+		}
+		out.Fields = append(out.Fields, PlacedField{Name: f.Name, Offset: aligned, Size: f.Type.Size})
+		offset = aligned + f.Type.Size
+	}
+
+	size := roundUp(offset, structAlign)
+	if gap := size - offset; gap > 0 {
+		out.Fields = append(out.Fields, PlacedField{
+			Name: fmt.Sprintf("_pad%d", padCount), Offset: offset, Size: gap, IsPadding: true,
+		})
+	}
+	out.Size = size
+	out.Align = structAlign
+	return out
+}
+
+// AssertionExpr returns the compile-time size assertion the c-abi emitter
+// appends after a struct. A single-sided `[computed - want]byte` only
+// catches an undersized struct: an array length has to be non-negative,
+// but any positive length — including one from an oversized struct —
+// compiles fine. Asserting both directions catches both: only size 0
+// satisfies `[computed-want]byte` and `[want-computed]byte` at once.
+func AssertionExpr(className string, want int) string {
+	return fmt.Sprintf(
+		"var _ [unsafe.Sizeof(%s{}) - %d]byte\nvar _ [%d - unsafe.Sizeof(%s{})]byte",
+		className, want, want, className,
+	)
+}
@@ -0,0 +1,124 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/cmc-labo/hybrid-transpiler/internal/ir"
+)
+
+func TestComputePointNoPadding(t *testing.T) {
+	point := ir.Class{
+		Name: "Point",
+		Fields: []ir.Field{
+			{Name: "X", Type: ir.Type{GoName: "int32", Size: 4, Align: 4}},
+			{Name: "Y", Type: ir.Type{GoName: "int32", Size: 4, Align: 4}},
+		},
+	}
+	got := Compute(point)
+	if got.Size != 8 || got.Align != 4 {
+		t.Fatalf("Point layout = size %d align %d, want size 8 align 4", got.Size, got.Align)
+	}
+	for _, f := range got.Fields {
+		if f.IsPadding {
+			t.Errorf("unexpected padding field %+v", f)
+		}
+	}
+}
+
+func TestComputeMixedStructNeedsPadding(t *testing.T) {
+	// struct { char c; double d; int16_t s; } under the Itanium ABI:
+	// c@0 (1), pad to 8, d@8 (8), s@16 (2), then pad the struct to its
+	// 8-byte alignment: total size 24.
+	mixed := ir.Class{
+		Name: "Mixed",
+		Fields: []ir.Field{
+			{Name: "C", Type: ir.Type{GoName: "int8", Size: 1, Align: 1}},
+			{Name: "D", Type: ir.Type{GoName: "float64", Size: 8, Align: 8}},
+			{Name: "S", Type: ir.Type{GoName: "int16", Size: 2, Align: 2}},
+		},
+	}
+	got := Compute(mixed)
+	if got.Align != 8 {
+		t.Fatalf("align = %d, want 8", got.Align)
+	}
+	if got.Size != 24 {
+		t.Fatalf("size = %d, want 24", got.Size)
+	}
+
+	wantOffsets := map[string]int{"C": 0, "D": 8, "S": 16}
+	for name, wantOffset := range wantOffsets {
+		found := false
+		for _, f := range got.Fields {
+			if f.Name == name {
+				found = true
+				if f.Offset != wantOffset {
+					t.Errorf("field %s offset = %d, want %d", name, f.Offset, wantOffset)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("field %s missing from layout", name)
+		}
+	}
+
+	var padBytes int
+	for _, f := range got.Fields {
+		if f.IsPadding {
+			padBytes += f.Size
+		}
+	}
+	if padBytes != 7+6 {
+		t.Errorf("total padding = %d, want 13 (7 before D, 6 trailing)", padBytes)
+	}
+}
+
+func TestComputeRespectsPackPragma(t *testing.T) {
+	packed := ir.Class{
+		Name:       "Mixed",
+		PackPragma: 1,
+		Fields: []ir.Field{
+			{Name: "C", Type: ir.Type{GoName: "int8", Size: 1, Align: 1}},
+			{Name: "D", Type: ir.Type{GoName: "float64", Size: 8, Align: 8}},
+		},
+	}
+	got := Compute(packed)
+	if got.Size != 9 || got.Align != 1 {
+		t.Fatalf("packed layout = size %d align %d, want size 9 align 1", got.Size, got.Align)
+	}
+	for _, f := range got.Fields {
+		if f.IsPadding {
+			t.Errorf("unexpected padding field %+v with #pragma pack(1)", f)
+		}
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Mode
+		wantErr bool
+	}{
+		{"", Native, false},
+		{"native", Native, false},
+		{"c-abi", CABI, false},
+		{"bogus", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMode(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMode(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMode(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAssertionExpr(t *testing.T) {
+	got := AssertionExpr("Point", 8)
+	want := "var _ [unsafe.Sizeof(Point{}) - 8]byte\nvar _ [8 - unsafe.Sizeof(Point{})]byte"
+	if got != want {
+		t.Errorf("AssertionExpr = %q, want %q", got, want)
+	}
+}
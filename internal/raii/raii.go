@@ -0,0 +1,102 @@
+// Package raii lowers C++ RAII destructors to Go. Go has no scope-exit
+// destructor call, so a class with a non-trivial destructor gets an
+// explicit Release method plus, depending on policy and how the class is
+// constructed, automatic wiring to call it.
+package raii
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cmc-labo/hybrid-transpiler/internal/ir"
+)
+
+// Policy is the value of the --raii flag.
+type Policy string
+
+const (
+	// Finalizer wires heap-constructed instances with
+	// runtime.SetFinalizer. Not deterministic: a finalizer runs at some
+	// GC pass after the object becomes unreachable, not at a predictable
+	// point the way a C++ destructor runs at scope exit.
+	Finalizer Policy = "finalizer"
+	// Defer wires stack-constructed instances with a deferred Release
+	// call at the end of their declaring scope.
+	Defer Policy = "defer"
+	// Both applies Finalizer to heap-constructed instances and Defer to
+	// stack-constructed ones — the default heuristic.
+	Both Policy = "both"
+	// Explicit emits Release but wires nothing automatically; the caller
+	// is responsible for calling it.
+	Explicit Policy = "explicit"
+)
+
+// ParsePolicy parses the --raii flag value, defaulting to Both.
+func ParsePolicy(s string) (Policy, error) {
+	switch Policy(s) {
+	case "", Both:
+		return Both, nil
+	case Finalizer:
+		return Finalizer, nil
+	case Defer:
+		return Defer, nil
+	case Explicit:
+		return Explicit, nil
+	default:
+		return "", fmt.Errorf("raii: unknown --raii value %q (want finalizer, defer, both, or explicit)", s)
+	}
+}
+
+// receiverName derives the receiver identifier for c's generated methods
+// from its first letter, lowercased — the same convention the rest of
+// the emitted code already follows (Resource -> r, FileHandle -> f).
+// c.Destructor.Body is written against this same letter, so it can't be
+// hardcoded independently of the class name.
+func receiverName(c ir.Class) string {
+	return strings.ToLower(c.Name[:1])
+}
+
+// GenerateRelease emits the Release method body for a class with a
+// non-trivial destructor. A released bool field guards against
+// double-release, since the finalizer can fire after an explicit Release
+// call under Finalizer/Both.
+func GenerateRelease(c ir.Class) string {
+	r := receiverName(c)
+	return fmt.Sprintf(`func (%s *%s) Release() {
+	if %s.released {
+		return
+	}
+	%s.released = true
+	%s
+}`, r, c.Name, r, r, c.Destructor.Body)
+}
+
+// ConstructorFinalizer returns the runtime.SetFinalizer call to insert in
+// a heap constructor, or "" if policy/allocation don't call for one.
+// Finalizer/Both only apply to heap-constructed classes: a value type
+// returned from a stack-style constructor has no pointer identity yet for
+// SetFinalizer to attach to.
+func ConstructorFinalizer(c ir.Class, policy Policy) string {
+	if !c.HeapConstructed {
+		return ""
+	}
+	if policy != Finalizer && policy != Both {
+		return ""
+	}
+	return fmt.Sprintf("runtime.SetFinalizer(%s, (*%s).Release)", receiverName(c), c.Name)
+}
+
+// ScopeDefer returns the deferred Release call to insert at the end of
+// the scope a stack-constructed instance was declared in, or "" if
+// policy/allocation don't call for one. Defer/Both only apply to
+// stack-constructed classes — a heap instance outlives the constructor's
+// scope by design, so there's no single scope exit to defer to.
+func ScopeDefer(varName string, c ir.Class, policy Policy) string {
+	if c.HeapConstructed {
+		return ""
+	}
+	if policy != Defer && policy != Both {
+		return ""
+	}
+	return fmt.Sprintf("defer %s.Release()", varName)
+}
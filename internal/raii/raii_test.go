@@ -0,0 +1,126 @@
+package raii
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cmc-labo/hybrid-transpiler/internal/ir"
+)
+
+func heapClass() ir.Class {
+	return ir.Class{Name: "Resource", HeapConstructed: true, Destructor: &ir.Destructor{Body: "r.Data = nil"}}
+}
+
+func stackClass() ir.Class {
+	return ir.Class{Name: "FileHandle", HeapConstructed: false, Destructor: &ir.Destructor{Body: "f.fd = -1"}}
+}
+
+func TestGenerateRelease(t *testing.T) {
+	out := GenerateRelease(heapClass())
+	for _, want := range []string{
+		"func (r *Resource) Release() {",
+		"if r.released {",
+		"r.released = true",
+		"r.Data = nil",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("GenerateRelease missing %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestGenerateReleaseDerivesReceiverFromClassName(t *testing.T) {
+	out := GenerateRelease(stackClass())
+	for _, want := range []string{
+		"func (f *FileHandle) Release() {",
+		"if f.released {",
+		"f.released = true",
+		"f.fd = -1",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("GenerateRelease missing %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "r.") {
+		t.Errorf("GenerateRelease used hardcoded receiver %q on a non-Resource class, got:\n%s", "r", out)
+	}
+}
+
+func TestConstructorFinalizer(t *testing.T) {
+	tests := []struct {
+		name   string
+		class  ir.Class
+		policy Policy
+		want   string
+	}{
+		{"heap + finalizer", heapClass(), Finalizer, "runtime.SetFinalizer(r, (*Resource).Release)"},
+		{"heap + both", heapClass(), Both, "runtime.SetFinalizer(r, (*Resource).Release)"},
+		{"heap + defer", heapClass(), Defer, ""},
+		{"heap + explicit", heapClass(), Explicit, ""},
+		{"stack + finalizer", stackClass(), Finalizer, ""},
+		{"stack + both", stackClass(), Both, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConstructorFinalizer(tt.class, tt.policy); got != tt.want {
+				t.Errorf("ConstructorFinalizer() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConstructorFinalizerDerivesReceiverFromClassName(t *testing.T) {
+	c := ir.Class{Name: "Widget", HeapConstructed: true, Destructor: &ir.Destructor{Body: "w.handle = nil"}}
+	want := "runtime.SetFinalizer(w, (*Widget).Release)"
+	if got := ConstructorFinalizer(c, Finalizer); got != want {
+		t.Errorf("ConstructorFinalizer() = %q, want %q", got, want)
+	}
+}
+
+func TestScopeDefer(t *testing.T) {
+	tests := []struct {
+		name   string
+		class  ir.Class
+		policy Policy
+		want   string
+	}{
+		{"stack + defer", stackClass(), Defer, "defer f.Release()"},
+		{"stack + both", stackClass(), Both, "defer f.Release()"},
+		{"stack + finalizer", stackClass(), Finalizer, ""},
+		{"stack + explicit", stackClass(), Explicit, ""},
+		{"heap + defer", heapClass(), Defer, ""},
+		{"heap + both", heapClass(), Both, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ScopeDefer("f", tt.class, tt.policy); got != tt.want {
+				t.Errorf("ScopeDefer() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Policy
+		wantErr bool
+	}{
+		{"", Both, false},
+		{"both", Both, false},
+		{"finalizer", Finalizer, false},
+		{"defer", Defer, false},
+		{"explicit", Explicit, false},
+		{"sometimes", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePolicy(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePolicy(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParsePolicy(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package sharedptr
+
+import "sort"
+
+// DetectCycles finds classes involved in a cycle in the source's
+// shared_ptr graph (graph[class] lists the names of other classes it
+// holds a shared_ptr to). --shared-ptr=gc is only sound when this
+// returns no cycles: a reference cycle of plain Go pointers is never
+// collected, where a reference-counted Shared[T] cycle is at least
+// visible as a leak the same way the original C++ would leak.
+func DetectCycles(graph map[string][]string) []string {
+	const (
+		white = iota
+		gray
+		black
+	)
+	color := make(map[string]int, len(graph))
+	inCycle := map[string]bool{}
+
+	var visit func(node string, stack []string)
+	visit = func(node string, stack []string) {
+		color[node] = gray
+		stack = append(stack, node)
+		for _, next := range graph[node] {
+			switch color[next] {
+			case white:
+				visit(next, stack)
+			case gray:
+				// Found a back edge to `next`: everything in stack from
+				// next's position onward is part of the cycle.
+				for i := len(stack) - 1; i >= 0; i-- {
+					inCycle[stack[i]] = true
+					if stack[i] == next {
+						break
+					}
+				}
+			}
+		}
+		color[node] = black
+	}
+
+	// Deterministic iteration order for reproducible output.
+	nodes := make([]string, 0, len(graph))
+	for n := range graph {
+		nodes = append(nodes, n)
+	}
+	sort.Strings(nodes)
+
+	for _, n := range nodes {
+		if color[n] == white {
+			visit(n, nil)
+		}
+	}
+
+	names := make([]string, 0, len(inCycle))
+	for n := range inCycle {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GCSafe reports whether --shared-ptr=gc is safe for the given
+// shared_ptr graph, and the classes involved in a cycle if it isn't.
+func GCSafe(graph map[string][]string) (safe bool, cyclic []string) {
+	cyclic = DetectCycles(graph)
+	return len(cyclic) == 0, cyclic
+}
@@ -0,0 +1,56 @@
+package sharedptr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectCyclesNoCycle(t *testing.T) {
+	// Rectangle holds a shared_ptr to Point, and nothing points back: a DAG.
+	graph := map[string][]string{
+		"Rectangle": {"Point"},
+		"Point":     nil,
+	}
+	if got := DetectCycles(graph); len(got) != 0 {
+		t.Errorf("DetectCycles(DAG) = %v, want none", got)
+	}
+}
+
+func TestDetectCyclesDirectCycle(t *testing.T) {
+	// Parent <-> Child holding shared_ptrs to each other.
+	graph := map[string][]string{
+		"Parent": {"Child"},
+		"Child":  {"Parent"},
+	}
+	got := DetectCycles(graph)
+	want := []string{"Child", "Parent"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectCycles(cycle) = %v, want %v", got, want)
+	}
+}
+
+func TestDetectCyclesIndirectCycle(t *testing.T) {
+	graph := map[string][]string{
+		"A": {"B"},
+		"B": {"C"},
+		"C": {"A"},
+		"D": {"A"}, // D points into the cycle but isn't part of it.
+	}
+	got := DetectCycles(graph)
+	want := []string{"A", "B", "C"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectCycles(indirect cycle) = %v, want %v", got, want)
+	}
+}
+
+func TestGCSafe(t *testing.T) {
+	dag := map[string][]string{"Rectangle": {"Point"}, "Point": nil}
+	if safe, cyclic := GCSafe(dag); !safe || len(cyclic) != 0 {
+		t.Errorf("GCSafe(DAG) = (%v, %v), want (true, none)", safe, cyclic)
+	}
+
+	cyclic := map[string][]string{"Parent": {"Child"}, "Child": {"Parent"}}
+	if safe, names := GCSafe(cyclic); safe || len(names) == 0 {
+		t.Errorf("GCSafe(cyclic) = (%v, %v), want (false, non-empty)", safe, names)
+	}
+}
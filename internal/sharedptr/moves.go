@@ -0,0 +1,52 @@
+package sharedptr
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Move records that a unique_ptr-typed variable was moved from at a
+// given source line (e.g. passed by std::move).
+type Move struct {
+	Var  string
+	Line int
+}
+
+// Use records a read of a variable at a given source line.
+type Use struct {
+	Var  string
+	Line int
+}
+
+// CheckMoves enforces unique_ptr move semantics: using a variable after
+// it was moved from is a transpile-time error, not a runtime nil
+// dereference. Returns one error per offending use, ordered by line.
+func CheckMoves(moves []Move, uses []Use) []error {
+	movedAt := map[string]int{}
+	for _, m := range moves {
+		if line, ok := movedAt[m.Var]; !ok || m.Line < line {
+			movedAt[m.Var] = m.Line
+		}
+	}
+
+	type offense struct {
+		line int
+		err  error
+	}
+	var offenses []offense
+	for _, u := range uses {
+		if line, ok := movedAt[u.Var]; ok && u.Line > line {
+			offenses = append(offenses, offense{
+				line: u.Line,
+				err:  fmt.Errorf("use of %q at line %d after move at line %d", u.Var, u.Line, line),
+			})
+		}
+	}
+	sort.Slice(offenses, func(i, j int) bool { return offenses[i].line < offenses[j].line })
+
+	errs := make([]error, len(offenses))
+	for i, o := range offenses {
+		errs[i] = o.err
+	}
+	return errs
+}
@@ -0,0 +1,28 @@
+package sharedptr
+
+import "testing"
+
+func TestCheckMoves(t *testing.T) {
+	moves := []Move{{Var: "p", Line: 10}}
+	uses := []Use{
+		{Var: "p", Line: 5},  // before the move: fine
+		{Var: "p", Line: 15}, // after the move: an error
+		{Var: "q", Line: 20}, // never moved: fine
+	}
+
+	errs := CheckMoves(moves, uses)
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors, want 1: %v", len(errs), errs)
+	}
+	want := `use of "p" at line 15 after move at line 10`
+	if errs[0].Error() != want {
+		t.Errorf("error = %q, want %q", errs[0].Error(), want)
+	}
+}
+
+func TestCheckMovesNoMoves(t *testing.T) {
+	uses := []Use{{Var: "p", Line: 1}}
+	if errs := CheckMoves(nil, uses); len(errs) != 0 {
+		t.Errorf("got %d errors with no moves, want 0", len(errs))
+	}
+}
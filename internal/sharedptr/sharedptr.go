@@ -0,0 +1,106 @@
+// Package sharedptr lowers C++ smart pointers to Go.
+package sharedptr
+
+import "fmt"
+
+// Mode is the value of the --shared-ptr flag.
+type Mode string
+
+const (
+	// RC lowers shared_ptr<T> to a generated Shared[T] wrapper that
+	// tracks its own reference count. The default, since it's sound
+	// regardless of the shape of the source's shared_ptr graph.
+	RC Mode = "rc"
+	// GC lowers shared_ptr<T> to plain *T, relying on Go's garbage
+	// collector instead of manual refcounting. Only sound when the
+	// source's shared_ptr graph has no cycles — see GCSafe.
+	GC Mode = "gc"
+)
+
+// ParseMode parses the --shared-ptr flag value, defaulting to RC.
+func ParseMode(s string) (Mode, error) {
+	switch Mode(s) {
+	case "", RC:
+		return RC, nil
+	case GC:
+		return GC, nil
+	default:
+		return "", fmt.Errorf("sharedptr: unknown --shared-ptr value %q (want rc or gc)", s)
+	}
+}
+
+// SharedTypeDecl is the generated Shared[T] wrapper, emitted once per
+// output file that needs it.
+const SharedTypeDecl = `type Shared[T any] struct {
+	ptr *T
+	rc  *atomic.Int64
+}
+
+func NewShared[T any](v T) Shared[T] {
+	rc := &atomic.Int64{}
+	rc.Store(1)
+	return Shared[T]{ptr: &v, rc: rc}
+}
+
+func (s Shared[T]) Clone() Shared[T] {
+	s.rc.Add(1)
+	return s
+}
+
+// Release must take a pointer receiver: it nils out s.ptr on the caller's
+// own handle once the refcount hits zero, not on a throwaway copy.
+func (s *Shared[T]) Release() {
+	if s.rc.Add(-1) == 0 {
+		s.ptr = nil
+	}
+}
+
+func (s Shared[T]) Weaken() Weak[T] {
+	return Weak[T]{ptr: s.ptr, rc: s.rc}
+}`
+
+// WeakTypeDecl is the generated Weak[T] wrapper.
+const WeakTypeDecl = `type Weak[T any] struct {
+	ptr *T
+	rc  *atomic.Int64
+}
+
+func (w Weak[T]) Lock() *T {
+	if w.rc.Load() == 0 {
+		return nil
+	}
+	return w.ptr
+}`
+
+// EffectiveMode returns the shared_ptr mode actually used for a class:
+// Go has no weak reference mechanism, so any class reachable through a
+// weak_ptr needs the rc-backed Shared[T]/Weak[T] pair regardless of the
+// globally requested mode — there'd be nothing for Weak[T].Lock to check
+// otherwise.
+func EffectiveMode(requested Mode, hasWeakRef bool) Mode {
+	if hasWeakRef {
+		return RC
+	}
+	return requested
+}
+
+// LowerUniquePtr lowers std::unique_ptr<T>; the type itself is just *T.
+// Post-move use is rejected by the caller using CheckMoves; clearing the
+// moved-from variable to nil is not done by this package.
+func LowerUniquePtr(elemGoType string) string {
+	return "*" + elemGoType
+}
+
+// LowerSharedPtr lowers std::shared_ptr<T> under the given effective mode
+// (see EffectiveMode).
+func LowerSharedPtr(elemGoType string, mode Mode) string {
+	if mode == GC {
+		return "*" + elemGoType
+	}
+	return fmt.Sprintf("Shared[%s]", elemGoType)
+}
+
+// LowerWeakPtr lowers std::weak_ptr<T>. Always Weak[T]: see EffectiveMode.
+func LowerWeakPtr(elemGoType string) string {
+	return fmt.Sprintf("Weak[%s]", elemGoType)
+}
@@ -0,0 +1,53 @@
+package sharedptr
+
+import "testing"
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Mode
+		wantErr bool
+	}{
+		{"", RC, false},
+		{"rc", RC, false},
+		{"gc", GC, false},
+		{"bogus", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMode(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMode(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMode(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEffectiveMode(t *testing.T) {
+	if got := EffectiveMode(GC, true); got != RC {
+		t.Errorf("EffectiveMode(GC, hasWeakRef=true) = %q, want %q (weak_ptr forces rc)", got, RC)
+	}
+	if got := EffectiveMode(GC, false); got != GC {
+		t.Errorf("EffectiveMode(GC, hasWeakRef=false) = %q, want %q", got, GC)
+	}
+	if got := EffectiveMode(RC, false); got != RC {
+		t.Errorf("EffectiveMode(RC, hasWeakRef=false) = %q, want %q", got, RC)
+	}
+}
+
+func TestLowerPointers(t *testing.T) {
+	if got := LowerUniquePtr("SharedData"); got != "*SharedData" {
+		t.Errorf("LowerUniquePtr = %q, want %q", got, "*SharedData")
+	}
+	if got := LowerSharedPtr("SharedData", RC); got != "Shared[SharedData]" {
+		t.Errorf("LowerSharedPtr(rc) = %q, want %q", got, "Shared[SharedData]")
+	}
+	if got := LowerSharedPtr("SharedData", GC); got != "*SharedData" {
+		t.Errorf("LowerSharedPtr(gc) = %q, want %q", got, "*SharedData")
+	}
+	if got := LowerWeakPtr("SharedData"); got != "Weak[SharedData]" {
+		t.Errorf("LowerWeakPtr = %q, want %q", got, "Weak[SharedData]")
+	}
+}
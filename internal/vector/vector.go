@@ -0,0 +1,110 @@
+// Package vector lowers std::vector locals to Go slices, distinguishing
+// the three common C++ construction patterns instead of collapsing them
+// all into make([]T, n), and maps the common mutating operations to their
+// Go equivalents.
+package vector
+
+import "fmt"
+
+// Construction is which of the three common C++ vector constructions a
+// local was built with.
+type Construction int
+
+const (
+	// FixedLen is std::vector<T> v(n): length n, zero-valued.
+	FixedLen Construction = iota
+	// Reserved is std::vector<T> v; v.reserve(n): length 0, capacity n.
+	Reserved
+	// Resized is v.resize(n): length n, called on a vector that may
+	// already hold elements.
+	Resized
+)
+
+// ResizeHelper is the generic helper resize-lowering calls through when
+// capacity is observable (see Lower). It's emitted once per output file
+// that needs it, not once per element type, since Go generics make a
+// single definition sufficient.
+const ResizeHelper = `func resizeSlice[T any](s []T, n int) []T {
+	if n <= len(s) {
+		return s[:n]
+	}
+	return append(s, make([]T, n-len(s))...)
+}`
+
+// Lower returns the Go expression that constructs a vector local given
+// how it was constructed in the source, its element's Go type, its size
+// expression n, and whether a dataflow scan (see IsCapacityObservable)
+// found that the local's capacity is ever read or passed onward.
+//
+// When capacity isn't observable, reserve's capacity hint and resize's
+// "may be called again" distinction both collapse into the plainer forms
+// C++ reserve()/resize() are equivalent to once nothing downstream can
+// tell the difference: reserve becomes a bare nil slice, and resize a
+// bare make, with no capacity preallocated beyond what the length already
+// guarantees.
+func Lower(kind Construction, elemGoType, n string, capacityObservable bool) string {
+	switch kind {
+	case Reserved:
+		if !capacityObservable {
+			return "nil"
+		}
+		return fmt.Sprintf("make([]%s, 0, %s)", elemGoType, n)
+	case Resized:
+		if !capacityObservable {
+			return fmt.Sprintf("make([]%s, %s)", elemGoType, n)
+		}
+		return fmt.Sprintf("resizeSlice[%s](nil, %s)", elemGoType, n)
+	default: // FixedLen
+		return fmt.Sprintf("make([]%s, %s)", elemGoType, n)
+	}
+}
+
+// MapOp lowers a single vector operation call. elemGoType is only used by
+// emplace_back, to build the element's composite literal.
+func MapOp(op, recv, elemGoType string, args ...string) (string, error) {
+	switch op {
+	case "push_back":
+		if len(args) != 1 {
+			return "", fmt.Errorf("vector: push_back takes 1 argument, got %d", len(args))
+		}
+		return fmt.Sprintf("%s = append(%s, %s)", recv, recv, args[0]), nil
+	case "emplace_back":
+		return fmt.Sprintf("%s = append(%s, %s{%s})", recv, recv, elemGoType, joinArgs(args)), nil
+	case "size":
+		return fmt.Sprintf("len(%s)", recv), nil
+	case "capacity":
+		return fmt.Sprintf("cap(%s)", recv), nil
+	case "clear":
+		// Deliberately v[:0], not v = nil: C++ clear() keeps the
+		// underlying allocation, and nil would throw it away.
+		return fmt.Sprintf("%s = %s[:0]", recv, recv), nil
+	default:
+		return "", fmt.Errorf("vector: unmapped operation %q", op)
+	}
+}
+
+func joinArgs(args []string) string {
+	out := ""
+	for i, a := range args {
+		if i > 0 {
+			out += ", "
+		}
+		out += a
+	}
+	return out
+}
+
+// IsCapacityObservable stands in for the dataflow pass over a vector
+// local: given the operations seen performed on it (by name, e.g.
+// "capacity", "push_back", "escapes"), reports whether its capacity is
+// ever read or could be observed by code outside this lowering — a
+// direct v.capacity() call, or the vector being passed somewhere (by
+// pointer/reference) this pass can't see into.
+func IsCapacityObservable(ops []string) bool {
+	for _, op := range ops {
+		if op == "capacity" || op == "escapes" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package vector
+
+import "testing"
+
+func TestLower(t *testing.T) {
+	tests := []struct {
+		name       string
+		kind       Construction
+		observable bool
+		want       string
+	}{
+		{"fixed len", FixedLen, false, "make([]int32, n)"},
+		{"fixed len, observable has no effect", FixedLen, true, "make([]int32, n)"},
+		{"reserve, capacity not observable", Reserved, false, "nil"},
+		{"reserve, capacity observable", Reserved, true, "make([]int32, 0, n)"},
+		{"resize, capacity not observable", Resized, false, "make([]int32, n)"},
+		{"resize, capacity observable", Resized, true, "resizeSlice[int32](nil, n)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Lower(tt.kind, "int32", "n", tt.observable); got != tt.want {
+				t.Errorf("Lower() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapOp(t *testing.T) {
+	tests := []struct {
+		op      string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{"push_back", []string{"x"}, "v = append(v, x)", false},
+		{"emplace_back", []string{"1", "2"}, "v = append(v, Point{1, 2})", false},
+		{"size", nil, "len(v)", false},
+		{"capacity", nil, "cap(v)", false},
+		{"clear", nil, "v = v[:0]", false},
+		{"shrink_to_fit", nil, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.op, func(t *testing.T) {
+			got, err := MapOp(tt.op, "v", "Point", tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MapOp(%q) error = %v, wantErr %v", tt.op, err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("MapOp(%q) = %q, want %q", tt.op, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsCapacityObservable(t *testing.T) {
+	if IsCapacityObservable([]string{"push_back", "clear"}) {
+		t.Error("push_back/clear alone shouldn't make capacity observable")
+	}
+	if !IsCapacityObservable([]string{"push_back", "capacity"}) {
+		t.Error("a capacity() read should make capacity observable")
+	}
+	if !IsCapacityObservable([]string{"escapes"}) {
+		t.Error("a vector passed onward should make capacity observable")
+	}
+}